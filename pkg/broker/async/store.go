@@ -0,0 +1,129 @@
+package async
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// State is the terminal or in-flight state of an asynchronous operation.
+type State string
+
+const (
+	StateInProgress State = "in progress"
+	StateSucceeded  State = "succeeded"
+	StateFailed     State = "failed"
+)
+
+// configMapPrefix namespaces the ConfigMaps this store owns from anything else living in the
+// broker namespace.
+const configMapPrefix = "async-op-"
+
+// Operation records the progress of a single asynchronous provisioning or deprovisioning
+// operation: which step it's on, how many attempts have been made, and the last error seen.
+// Persisting it as a ConfigMap lets LastOperation answer without re-polling the cluster and
+// lets the broker resume tracking in-flight work after a pod restart.
+type Operation struct {
+	InstanceID      string                 `json:"instanceId"`
+	Step            string                 `json:"step"`
+	Attempts        int                    `json:"attempts"`
+	LastError       string                 `json:"lastError,omitempty"`
+	State           State                  `json:"state"`
+	Description     string                 `json:"description,omitempty"`
+	BrokerNamespace string                 `json:"brokerNamespace,omitempty"`
+	UserNamespace   string                 `json:"userNamespace,omitempty"`
+	Username        string                 `json:"username,omitempty"`
+	Parameters      map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// Store persists Operations as ConfigMaps in a single namespace, keyed by instanceID.
+type Store struct {
+	namespace string
+	k8sclient kubernetes.Interface
+}
+
+func NewStore(namespace string, k8sclient kubernetes.Interface) *Store {
+	return &Store{namespace: namespace, k8sclient: k8sclient}
+}
+
+func (s *Store) configMapName(instanceID string) string {
+	return configMapPrefix + instanceID
+}
+
+// Get returns the current Operation record for instanceID, or a NotFound error if none has
+// been recorded yet.
+func (s *Store) Get(instanceID string) (*Operation, error) {
+	cm, err := s.k8sclient.CoreV1().ConfigMaps(s.namespace).Get(s.configMapName(instanceID), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	op := &Operation{}
+	if err := json.Unmarshal([]byte(cm.Data["operation"]), op); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal operation record for %s", instanceID)
+	}
+
+	return op, nil
+}
+
+// Save creates or updates the Operation record for op.InstanceID.
+func (s *Store) Save(op *Operation) error {
+	data, err := json.Marshal(op)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal operation record for %s", op.InstanceID)
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      s.configMapName(op.InstanceID),
+			Namespace: s.namespace,
+		},
+		Data: map[string]string{"operation": string(data)},
+	}
+
+	cmClient := s.k8sclient.CoreV1().ConfigMaps(s.namespace)
+	_, err = cmClient.Create(cm)
+	if kerrors.IsAlreadyExists(err) {
+		_, err = cmClient.Update(cm)
+	}
+	return errors.Wrapf(err, "failed to save operation record for %s", op.InstanceID)
+}
+
+// List returns every Operation currently recorded in the store. Callers use this at startup to
+// find work a prior pod left StateInProgress and re-enqueue it, since the in-memory workqueue
+// an operation was originally queued on doesn't survive a restart.
+func (s *Store) List() ([]*Operation, error) {
+	cms, err := s.k8sclient.CoreV1().ConfigMaps(s.namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list operation records")
+	}
+
+	ops := make([]*Operation, 0, len(cms.Items))
+	for _, cm := range cms.Items {
+		if !strings.HasPrefix(cm.Name, configMapPrefix) {
+			continue
+		}
+
+		op := &Operation{}
+		if err := json.Unmarshal([]byte(cm.Data["operation"]), op); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal operation record %s", cm.Name)
+		}
+		ops = append(ops, op)
+	}
+
+	return ops, nil
+}
+
+// Delete removes the Operation record for instanceID, e.g. once deprovisioning has completed.
+func (s *Store) Delete(instanceID string) error {
+	err := s.k8sclient.CoreV1().ConfigMaps(s.namespace).Delete(s.configMapName(instanceID), &metav1.DeleteOptions{})
+	if err != nil && kerrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,104 @@
+package async
+
+import (
+	"sync"
+
+	glog "github.com/sirupsen/logrus"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// ReconcileFunc performs one attempt at provisioning or deprovisioning the instance identified
+// by key. Returning an error requeues the key with backoff; a nil return marks it done.
+type ReconcileFunc func(key string) error
+
+// Worker drains a rate-limited workqueue with a bounded pool of goroutines, so a burst of
+// concurrent Deploy/RemoveDeploy calls can't exhaust the API server's QPS budget.
+type Worker struct {
+	queue     workqueue.RateLimitingInterface
+	reconcile ReconcileFunc
+	workers   int
+
+	// cancelled tracks keys Cancel has been called for. workqueue.RateLimitingInterface has
+	// no way to drop a key already sitting in its internal delaying queue, so a cancelled key
+	// already backed off from a prior failure would otherwise still fire once its delay
+	// elapses; this set is checked in processNextItem so that fires as a no-op instead.
+	mu        sync.Mutex
+	cancelled map[string]struct{}
+}
+
+func NewWorker(name string, reconcile ReconcileFunc, workers int) *Worker {
+	return &Worker{
+		queue:     workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), name),
+		reconcile: reconcile,
+		workers:   workers,
+		cancelled: map[string]struct{}{},
+	}
+}
+
+// Start launches the worker pool. It blocks until stopCh is closed.
+func (w *Worker) Start(stopCh <-chan struct{}) {
+	for i := 0; i < w.workers; i++ {
+		go w.runWorker()
+	}
+	<-stopCh
+	w.queue.ShutDown()
+}
+
+// Enqueue schedules key for reconciliation, clearing any earlier cancellation. The cancellation
+// clear and the queue add happen under the same lock as Cancel's writes, so a concurrent Cancel
+// for the same key can't interleave and leave key enqueued yet marked cancelled.
+func (w *Worker) Enqueue(key string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.cancelled, key)
+	w.queue.Add(key)
+}
+
+// Cancel marks key so any pending or still-backing-off reconcile for it becomes a no-op
+// instead of firing, e.g. because a RemoveDeploy superseded an in-flight Deploy for the same
+// instance. See Enqueue for why this shares its lock.
+func (w *Worker) Cancel(key string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.cancelled[key] = struct{}{}
+	w.queue.Forget(key)
+}
+
+func (w *Worker) isCancelled(key string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, ok := w.cancelled[key]
+	return ok
+}
+
+func (w *Worker) runWorker() {
+	for w.processNextItem() {
+	}
+}
+
+func (w *Worker) processNextItem() bool {
+	key, quit := w.queue.Get()
+	if quit {
+		return false
+	}
+	defer w.queue.Done(key)
+
+	k := key.(string)
+	if w.isCancelled(k) {
+		glog.Infof("skipping reconcile for %s: cancelled", k)
+		w.queue.Forget(key)
+		return true
+	}
+
+	if err := w.reconcile(k); err != nil {
+		glog.Errorf("reconcile failed for %s, requeuing: %+v", k, err)
+		w.queue.AddRateLimited(key)
+		return true
+	}
+
+	w.queue.Forget(key)
+	return true
+}
@@ -0,0 +1,63 @@
+package async
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWorkerCancelSkipsReconcile(t *testing.T) {
+	called := false
+	w := NewWorker("test", func(key string) error {
+		called = true
+		return nil
+	}, 1)
+
+	w.Enqueue("instance-1")
+	w.Cancel("instance-1")
+
+	if !w.processNextItem() {
+		t.Fatal("expected processNextItem to return true")
+	}
+
+	if called {
+		t.Error("expected reconcile not to be called for a cancelled key")
+	}
+}
+
+func TestWorkerEnqueueAfterCancelRunsReconcile(t *testing.T) {
+	calls := 0
+	w := NewWorker("test", func(key string) error {
+		calls++
+		return nil
+	}, 1)
+
+	w.Enqueue("instance-1")
+	w.Cancel("instance-1")
+	w.processNextItem()
+
+	w.Enqueue("instance-1")
+	w.processNextItem()
+
+	if calls != 1 {
+		t.Errorf("expected reconcile to run exactly once after re-enqueue, got %d", calls)
+	}
+}
+
+func TestWorkerRequeuesOnError(t *testing.T) {
+	attempts := 0
+	w := NewWorker("test", func(key string) error {
+		attempts++
+		if attempts < 2 {
+			return errors.New("boom")
+		}
+		return nil
+	}, 1)
+
+	w.Enqueue("instance-1")
+	w.processNextItem()
+	w.processNextItem()
+
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts after a retry, got %d", attempts)
+	}
+}
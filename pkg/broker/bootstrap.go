@@ -0,0 +1,31 @@
+package broker
+
+import (
+	"k8s.io/client-go/kubernetes"
+)
+
+// Broker dispatches OSB requests to whichever registered Deployer handles a given service ID.
+type Broker struct {
+	deployers []Deployer
+}
+
+// NewBroker is the broker's startup integration point: it starts the deployer-config watcher
+// against the named ConfigMap and instantiates every deployer that has self-registered via
+// Register, building the dispatch table that used to be a hardcoded list of deployer structs.
+// stopCh shuts the config watcher down when the broker process exits.
+func NewBroker(id string, k8sclient kubernetes.Interface, configNamespace, configMapName string, stopCh <-chan struct{}) *Broker {
+	WatchConfig(k8sclient, configNamespace, configMapName, stopCh)
+
+	return &Broker{deployers: Deployers(id)}
+}
+
+// DeployerFor returns the registered Deployer that handles serviceID, or false if none does.
+func (b *Broker) DeployerFor(serviceID string) (Deployer, bool) {
+	for _, d := range b.deployers {
+		if d.IsForService(serviceID) {
+			return d, true
+		}
+	}
+
+	return nil, false
+}
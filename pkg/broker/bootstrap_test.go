@@ -0,0 +1,53 @@
+package broker
+
+import (
+	"testing"
+
+	"github.com/integr8ly/managed-service-broker/pkg/clients/openshift"
+	"k8s.io/api/authentication/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type fakeDeployer struct {
+	id        string
+	serviceID string
+}
+
+func (f *fakeDeployer) GetID() string                     { return f.id }
+func (f *fakeDeployer) IsForService(serviceID string) bool { return serviceID == f.serviceID }
+func (f *fakeDeployer) GetCatalogEntries() []*Service      { return nil }
+func (f *fakeDeployer) Deploy(instanceID, brokerNamespace string, contextProfile ContextProfile, parameters map[string]interface{}, userInfo v1.UserInfo, k8sclient kubernetes.Interface, osClientFactory *openshift.ClientFactory) (*CreateServiceInstanceResponse, error) {
+	return nil, nil
+}
+func (f *fakeDeployer) RemoveDeploy(serviceInstanceId string, namespace string, k8sclient kubernetes.Interface) error {
+	return nil
+}
+func (f *fakeDeployer) LastOperation(instanceID string, k8sclient kubernetes.Interface, osclient *openshift.ClientFactory, operation string) (*LastOperationResponse, error) {
+	return nil, nil
+}
+func (f *fakeDeployer) Update(instanceID string, parameters map[string]interface{}) (*UpdateServiceInstanceResponse, error) {
+	return nil, nil
+}
+
+func TestDeployerForFindsRegisteredService(t *testing.T) {
+	b := &Broker{deployers: []Deployer{
+		&fakeDeployer{id: "id", serviceID: "fuse-service-id"},
+		&fakeDeployer{id: "id", serviceID: "other-service-id"},
+	}}
+
+	d, ok := b.DeployerFor("fuse-service-id")
+	if !ok {
+		t.Fatal("expected a deployer to be found for fuse-service-id")
+	}
+	if !d.IsForService("fuse-service-id") {
+		t.Error("expected the returned deployer to match fuse-service-id")
+	}
+}
+
+func TestDeployerForReturnsFalseWhenUnregistered(t *testing.T) {
+	b := &Broker{deployers: []Deployer{&fakeDeployer{id: "id", serviceID: "fuse-service-id"}}}
+
+	if _, ok := b.DeployerFor("unknown-service-id"); ok {
+		t.Error("expected no deployer to be found for an unregistered service id")
+	}
+}
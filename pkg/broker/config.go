@@ -0,0 +1,103 @@
+package broker
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	glog "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// SyndesisConfig covers the fuse deployer's Syndesis custom resource defaults in the mounted
+// ConfigMap. The fuse deployer applies IntegrationsLimit as the default integrations limit of
+// new instances; TagSelection and MemoryRequest are parsed but not read by any deployer.
+type SyndesisConfig struct {
+	IntegrationsLimit int    `json:"integrationsLimit,omitempty"`
+	TagSelection      string `json:"tagSelection,omitempty"`
+	MemoryRequest     string `json:"memoryRequest,omitempty"`
+}
+
+// DeployerConfig holds the tunables parsed from the mounted ConfigMap: image versions, route
+// suffix, resource limits, and any service-specific section. Of the shared fields only
+// RouteSuffix is read by a deployer; ImageVersions and ResourceLimits are not. Deployers
+// should call Config() fresh on every use rather than caching a copy, since it's replaced
+// whenever the backing ConfigMap changes.
+type DeployerConfig struct {
+	ImageVersions  map[string]string `json:"imageVersions,omitempty"`
+	RouteSuffix    string            `json:"routeSuffix,omitempty"`
+	ResourceLimits map[string]string `json:"resourceLimits,omitempty"`
+	Syndesis       SyndesisConfig    `json:"syndesis,omitempty"`
+}
+
+var (
+	configMu      sync.RWMutex
+	currentConfig = DeployerConfig{}
+)
+
+// Config returns the most recently observed deployer configuration.
+func Config() DeployerConfig {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return currentConfig
+}
+
+func setConfig(cm *corev1.ConfigMap) {
+	data := cm.Data["config.json"]
+	if data == "" {
+		return
+	}
+
+	cfg := DeployerConfig{}
+	if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+		glog.Errorf("failed to unmarshal deployer config from configmap %s: %+v", cm.Name, err)
+		return
+	}
+
+	configMu.Lock()
+	currentConfig = cfg
+	configMu.Unlock()
+
+	glog.Infof("reloaded deployer config from configmap %s", cm.Name)
+}
+
+// WatchConfig starts a SharedIndexInformer over the named ConfigMap and keeps Config() up to
+// date as it changes, so the values deployers read from it can be changed at runtime without
+// rebuilding the broker image. Parameter schemas are not part of it. It does not block;
+// stopCh shuts the informer down.
+func WatchConfig(k8sclient kubernetes.Interface, namespace, name string, stopCh <-chan struct{}) {
+	nameSelector := fields.OneTermEqualSelector("metadata.name", name).String()
+
+	listWatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = nameSelector
+			return k8sclient.CoreV1().ConfigMaps(namespace).List(options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = nameSelector
+			return k8sclient.CoreV1().ConfigMaps(namespace).Watch(options)
+		},
+	}
+
+	informer := cache.NewSharedIndexInformer(listWatch, &corev1.ConfigMap{}, 10*time.Minute, cache.Indexers{})
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if cm, ok := obj.(*corev1.ConfigMap); ok {
+				setConfig(cm)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if cm, ok := newObj.(*corev1.ConfigMap); ok {
+				setConfig(cm)
+			}
+		},
+	})
+
+	go informer.Run(stopCh)
+}
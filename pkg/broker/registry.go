@@ -0,0 +1,58 @@
+package broker
+
+import (
+	"sync"
+
+	"github.com/integr8ly/managed-service-broker/pkg/clients/openshift"
+	glog "github.com/sirupsen/logrus"
+	"k8s.io/api/authentication/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Deployer is implemented by every managed service this broker can provision. Deployers
+// register a Factory under their service ID in init() via Register, so adding a new managed
+// service doesn't require touching the dispatcher that picks one via IsForService.
+type Deployer interface {
+	GetID() string
+	IsForService(serviceID string) bool
+	GetCatalogEntries() []*Service
+	Deploy(instanceID, brokerNamespace string, contextProfile ContextProfile, parameters map[string]interface{}, userInfo v1.UserInfo, k8sclient kubernetes.Interface, osClientFactory *openshift.ClientFactory) (*CreateServiceInstanceResponse, error)
+	RemoveDeploy(serviceInstanceId string, namespace string, k8sclient kubernetes.Interface) error
+	LastOperation(instanceID string, k8sclient kubernetes.Interface, osclient *openshift.ClientFactory, operation string) (*LastOperationResponse, error)
+	Update(instanceID string, parameters map[string]interface{}) (*UpdateServiceInstanceResponse, error)
+}
+
+// Factory builds the per-instance Deployer state for id.
+type Factory func(id string) Deployer
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]Factory{}
+)
+
+// Register adds a deployer factory under serviceID. Call it from a deployer package's
+// init(); panicking here would take down the broker before main even runs, so a duplicate
+// registration is logged and the later one wins instead.
+func Register(serviceID string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[serviceID]; exists {
+		glog.Warnf("deployer already registered for service %s, overwriting", serviceID)
+	}
+	registry[serviceID] = factory
+}
+
+// Deployers instantiates every registered deployer factory with id. Call it once at broker
+// startup to build the dispatch table that used to be a hardcoded list of deployer structs.
+func Deployers(id string) []Deployer {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	deployers := make([]Deployer, 0, len(registry))
+	for _, factory := range registry {
+		deployers = append(deployers, factory(id))
+	}
+
+	return deployers
+}
@@ -5,27 +5,60 @@ import (
 	"net/http"
 	"os"
 	"strings"
-	"time"
+	"sync"
 
 	"k8s.io/api/authentication/v1"
 
 	brokerapi "github.com/integr8ly/managed-service-broker/pkg/broker"
+	"github.com/integr8ly/managed-service-broker/pkg/broker/async"
 	"github.com/integr8ly/managed-service-broker/pkg/clients/openshift"
-	appsv1 "github.com/openshift/client-go/apps/clientset/versioned/typed/apps/v1"
+	osappsv1 "github.com/openshift/api/apps/v1"
 	k8sClient "github.com/operator-framework/operator-sdk/pkg/k8sclient"
 	"github.com/operator-framework/operator-sdk/pkg/util/k8sutil"
 	"github.com/pkg/errors"
 	glog "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 )
 
+// asyncWorkerCount bounds how many Deploy/RemoveDeploy reconciliations run concurrently, so a
+// burst of simultaneous provisions doesn't exhaust the API server's QPS budget.
+const asyncWorkerCount = 4
+
+// maxDeployAttempts bounds how many attempts (the first one included) a failing Deploy gets
+// before the operation is given up on. Without a cap, a permanently-failing instance (bad parameters, RBAC denial, etc.)
+// would retry forever under the default controller rate limiter and LastOperation would report
+// "in progress" indefinitely, which never lets an OSB platform's polling loop terminate.
+const maxDeployAttempts = 10
+
 type FuseDeployer struct {
 	id string
+
+	workerOnce   sync.Once
+	recorderOnce sync.Once
+	store        *async.Store
+	worker       *async.Worker
+
+	broadcaster record.EventBroadcaster
+	recorder    record.EventRecorder
 }
 
 func NewDeployer(id string) *FuseDeployer {
-	return &FuseDeployer{id: id}
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartLogging(glog.Infof)
+
+	return &FuseDeployer{
+		id:          id,
+		broadcaster: broadcaster,
+		recorder:    broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "fuse-deployer"}),
+	}
 }
 
 func (fd *FuseDeployer) IsForService(serviceID string) bool {
@@ -34,89 +67,308 @@ func (fd *FuseDeployer) IsForService(serviceID string) bool {
 
 func (fd *FuseDeployer) GetCatalogEntries() []*brokerapi.Service {
 	glog.Infof("Getting fuse catalog entries")
-	return getCatalogServicesObj()
+
+	services := getCatalogServicesObj()
+	integrationsLimit := brokerapi.Config().Syndesis.IntegrationsLimit
+
+	for _, service := range services {
+		// Advertise the OSB spec's plan_updateable catalog flag so platforms actually send the
+		// PATCH requests Update exists to handle, instead of treating plan changes as unsupported.
+		service.PlanUpdateable = true
+
+		// Reflect the mounted ConfigMap's integrations limit in what's advertised to consumers,
+		// same as createFuseCustomResource's default for instances that don't override "limit".
+		if integrationsLimit > 0 {
+			service.Description = fmt.Sprintf("%s (default integrations limit: %d)", service.Description, integrationsLimit)
+		}
+	}
+
+	return services
 }
 
 func (fd *FuseDeployer) GetID() string {
 	return fd.id
 }
 
+// Deploy enqueues fuse provisioning and returns immediately; the actual resource creation
+// happens asynchronously on fd.worker (see ensureWorker and reconcileDeploy) so that a slow or
+// partially-failing provision doesn't tie up the HTTP handler. Work recorded in fd.store by a
+// prior broker pod is picked up again once the first Deploy of the new process starts the worker.
 func (fd *FuseDeployer) Deploy(instanceID, brokerNamespace string, contextProfile brokerapi.ContextProfile, parameters map[string]interface{}, userInfo v1.UserInfo, k8sclient kubernetes.Interface, osClientFactory *openshift.ClientFactory) (*brokerapi.CreateServiceInstanceResponse, error) {
 	glog.Infof("Deploying fuse from deployer, id: %s", instanceID)
 
-	// Namespace
-	ns, err := k8sclient.CoreV1().Namespaces().Create(getNamespaceObj("fuse-" + instanceID))
-	if err != nil {
-		glog.Errorf("failed to create fuse namespace: %+v", err)
+	fd.ensureWorker(brokerNamespace, k8sclient, osClientFactory)
+
+	op := &async.Operation{
+		InstanceID:      instanceID,
+		Step:            "enqueued",
+		State:           async.StateInProgress,
+		Description:     "fuse provisioning enqueued",
+		BrokerNamespace: brokerNamespace,
+		UserNamespace:   contextProfile.Namespace,
+		Username:        userInfo.Username,
+		Parameters:      parameters,
+	}
+	if err := fd.store.Save(op); err != nil {
+		glog.Errorf("failed to record initial operation state for %s: %+v", instanceID, err)
 		return &brokerapi.CreateServiceInstanceResponse{
 			Code: http.StatusInternalServerError,
-		}, errors.Wrap(err, "failed to create namespace for fuse service")
+		}, errors.Wrap(err, "failed to record initial operation state")
 	}
 
-	namespace := ns.ObjectMeta.Name
+	fd.worker.Enqueue(instanceID)
+
+	return &brokerapi.CreateServiceInstanceResponse{
+		Code:         http.StatusAccepted,
+		DashboardURL: "https://" + fd.getRouteHostname("fuse-"+instanceID),
+	}, nil
+}
+
+// ensureRecorder starts recording fd.recorder's events to the cluster the first time any of
+// Deploy/RemoveDeploy runs, using that first call's client. It's idempotent and separate from
+// ensureWorker so RemoveDeploy can call it too without depending on Deploy having run first in
+// this process — otherwise an instance removed right after a broker restart would only ever
+// log its events via glog, never emit real Event objects.
+func (fd *FuseDeployer) ensureRecorder(k8sclient kubernetes.Interface) {
+	fd.recorderOnce.Do(func() {
+		fd.broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: k8sclient.CoreV1().Events("")})
+	})
+}
+
+// ensureWorker lazily starts the shared async worker pool and operation store the first time
+// Deploy runs, using that first call's clients for every reconciliation. RemoveDeploy and
+// LastOperation don't call it, so until a Deploy has run in this process fd.store and
+// fd.worker are nil. The
+// clients are broker-wide, so this is safe across concurrent requests. It also re-enqueues any
+// operation a prior pod left StateInProgress, since the in-memory workqueue it was originally
+// queued on doesn't survive a restart and nothing else would ever reconcile that key again.
+func (fd *FuseDeployer) ensureWorker(brokerNamespace string, k8sclient kubernetes.Interface, osClientFactory *openshift.ClientFactory) {
+	fd.workerOnce.Do(func() {
+		fd.ensureRecorder(k8sclient)
+
+		fd.store = async.NewStore(brokerNamespace, k8sclient)
+		fd.worker = async.NewWorker("fuse-provisioning", func(instanceID string) error {
+			return fd.reconcileDeploy(instanceID, k8sclient, osClientFactory)
+		}, asyncWorkerCount)
+		go fd.worker.Start(make(chan struct{}))
+
+		ops, err := fd.store.List()
+		if err != nil {
+			glog.Errorf("failed to list in-flight operations on startup: %+v", err)
+		}
+		for _, op := range ops {
+			if op.State == async.StateInProgress {
+				glog.Infof("re-enqueuing in-flight operation %s after startup (step: %s)", op.InstanceID, op.Step)
+				fd.worker.Enqueue(op.InstanceID)
+			}
+		}
+	})
+}
+
+// recordEvent emits a Kubernetes Event scoped to the fuse namespace, with the instance ID and
+// requesting user written into the event message (the Event carries no annotations), so tenants get a `kubectl get events -n fuse-<id>` story
+// for debugging failed provisions and cluster admins get an audit trail that today only
+// exists as logrus lines.
+func (fd *FuseDeployer) recordEvent(namespace, instanceID, username, eventType, reason, message string) {
+	// Namespace is cluster-scoped, so corev1.Namespace/GetReference leaves ObjectReference.Namespace
+	// empty and the recorder falls back to filing the event under "default". Set it explicitly to
+	// namespace so `kubectl get events -n fuse-<id>` actually finds these.
+	involved := &corev1.ObjectReference{
+		Kind:       "Namespace",
+		APIVersion: "v1",
+		Name:       namespace,
+		Namespace:  namespace,
+	}
+
+	// message may be an arbitrary error string; Event (unlike Eventf) doesn't treat it as a
+	// format string, so a stray '%' in it can't produce garbled output.
+	fd.recorder.Event(involved, eventType, reason, fmt.Sprintf("instance %s (created-by %s): %s", instanceID, username, message))
+}
+
+// reconcileDeploy performs one attempt at provisioning instanceID, using the request state
+// recorded in fd.store by Deploy. It's safe to retry: every step it calls treats
+// AlreadyExists/conflict as something to reconcile rather than fail on.
+func (fd *FuseDeployer) reconcileDeploy(instanceID string, k8sclient kubernetes.Interface, osClientFactory *openshift.ClientFactory) error {
+	op, err := fd.store.Get(instanceID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load operation record for %s", instanceID)
+	}
+
+	if err := fd.doDeploy(instanceID, op, k8sclient, osClientFactory); err != nil {
+		op.Attempts++
+		op.LastError = err.Error()
+
+		if op.Attempts >= maxDeployAttempts {
+			op.State = async.StateFailed
+			op.Description = fmt.Sprintf("fuse provisioning failed after %d attempts: %s", op.Attempts, err.Error())
+			if saveErr := fd.store.Save(op); saveErr != nil {
+				glog.Errorf("failed to record failed operation for %s: %+v", instanceID, saveErr)
+			}
+			fd.recordEvent("fuse-"+instanceID, instanceID, op.Username, corev1.EventTypeWarning, "ProvisioningFailed", err.Error())
+			glog.Errorf("fuse provisioning for %s gave up after %d attempts: %+v", instanceID, op.Attempts, err)
+			// Returning nil here (rather than err) stops the worker from requeuing: the
+			// operation has already reached a terminal state, so there's nothing left to retry.
+			return nil
+		}
+
+		op.State = async.StateInProgress
+		if saveErr := fd.store.Save(op); saveErr != nil {
+			glog.Errorf("failed to record failed attempt for %s: %+v", instanceID, saveErr)
+		}
+		fd.recordEvent("fuse-"+instanceID, instanceID, op.Username, corev1.EventTypeWarning, "ProvisioningFailed", err.Error())
+		return err
+	}
+
+	op.State = async.StateSucceeded
+	op.Step = "done"
+	op.LastError = ""
+	op.Description = "fuse deployed successfully"
+	return fd.store.Save(op)
+}
+
+func (fd *FuseDeployer) doDeploy(instanceID string, op *async.Operation, k8sclient kubernetes.Interface, osClientFactory *openshift.ClientFactory) error {
+	namespace := "fuse-" + instanceID
+	userInfo := v1.UserInfo{Username: op.Username}
+
+	// Namespace
+	op.Step = "namespace"
+	ns, err := k8sclient.CoreV1().Namespaces().Create(getNamespaceObj(namespace))
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		glog.Errorf("failed to create fuse namespace: %+v", err)
+		return errors.Wrap(err, "failed to create namespace for fuse service")
+	}
+	if err == nil {
+		namespace = ns.ObjectMeta.Name
+	}
+	fd.recordEvent(namespace, instanceID, op.Username, corev1.EventTypeNormal, "NamespaceCreated", "created namespace "+namespace+" for fuse service")
 
 	// ServiceAccount
+	op.Step = "service-account"
 	_, err = k8sclient.CoreV1().ServiceAccounts(namespace).Create(getServiceAccountObj())
-	if err != nil {
+	if err != nil && !kerrors.IsAlreadyExists(err) {
 		glog.Errorf("failed to create fuse service account: %+v", err)
-		return &brokerapi.CreateServiceInstanceResponse{
-			Code: http.StatusInternalServerError,
-		}, errors.Wrap(err, "failed to create service account for fuse service")
+		return errors.Wrap(err, "failed to create service account for fuse service")
 	}
 
 	//Role
+	op.Step = "role"
 	_, err = k8sclient.RbacV1beta1().Roles(namespace).Create(getRoleObj())
-	if err != nil {
+	if err != nil && !kerrors.IsAlreadyExists(err) {
 		glog.Errorf("failed to create fuse role: %+v", err)
-		return &brokerapi.CreateServiceInstanceResponse{
-			Code: http.StatusInternalServerError,
-		}, errors.Wrap(err, "failed to create role for fuse service")
+		return errors.Wrap(err, "failed to create role for fuse service")
 	}
 
 	// RoleBindings
+	op.Step = "role-bindings"
 	err = fd.createRoleBindings(namespace, userInfo, k8sclient, osClientFactory)
 	if err != nil {
 		glog.Errorln(err)
-		return &brokerapi.CreateServiceInstanceResponse{
-			Code: http.StatusInternalServerError,
-		}, err
+		return err
 	}
 
 	// ImageStream
+	op.Step = "image-stream"
 	err = fd.createImageStream(namespace, osClientFactory)
 	if err != nil {
 		glog.Errorf("failed to create fuse image stream: %+v", err)
-		return &brokerapi.CreateServiceInstanceResponse{
-			Code: http.StatusInternalServerError,
-		}, err
+		return err
 	}
+	fd.recordEvent(namespace, instanceID, op.Username, corev1.EventTypeNormal, "ImageStreamCreated", "created fuse image streams")
 
 	// DeploymentConfig
+	op.Step = "deployment-config"
 	err = fd.createFuseOperator(namespace, osClientFactory)
 	if err != nil {
 		glog.Errorln(err)
-		return &brokerapi.CreateServiceInstanceResponse{
-			Code: http.StatusInternalServerError,
-		}, err
+		return err
 	}
 
 	// Fuse custom resource
-	dashboardURL, err := fd.createFuseCustomResource(namespace, brokerNamespace, contextProfile.Namespace, k8sclient, userInfo.Username, parameters)
+	op.Step = "custom-resource"
+	dashboardURL, err := fd.createFuseCustomResource(namespace, op.BrokerNamespace, op.UserNamespace, k8sclient, op.Username, op.Parameters)
 	if err != nil {
 		glog.Errorln(err)
-		return &brokerapi.CreateServiceInstanceResponse{
-			Code: http.StatusInternalServerError,
-		}, err
+		return err
 	}
+	fd.recordEvent(namespace, instanceID, op.Username, corev1.EventTypeNormal, "SyndesisCRApplied", "applied the syndesis custom resource")
 
-	return &brokerapi.CreateServiceInstanceResponse{
-		Code:         http.StatusAccepted,
-		DashboardURL: dashboardURL,
+	glog.Infof("fuse custom resource created for %s, dashboard at %s", instanceID, dashboardURL)
+	return nil
+}
+
+// Update diffs the existing Syndesis custom resource against the requested parameters and
+// patches any tunables that changed (currently integrations.limit), retrying on conflict
+// since the fuse operator may be reconciling the same resource concurrently. It satisfies
+// the OSB PATCH /v2/service_instances/:id contract so plan/parameter changes don't require
+// a full re-provision.
+func (fd *FuseDeployer) Update(instanceID string, parameters map[string]interface{}) (*brokerapi.UpdateServiceInstanceResponse, error) {
+	glog.Infof("Updating fuse from deployer, id: %s", instanceID)
+	namespace := "fuse-" + instanceID
+
+	fuseClient, _, err := k8sClient.GetResourceClient("syndesis.io/v1alpha1", "Syndesis", namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create fuse client")
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		existing, err := fuseClient.Get(namespace, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		changed := false
+		if limit, ok := parameters["limit"]; ok {
+			newLimit := int64(limit.(float64))
+			currentLimit, found, _ := unstructured.NestedInt64(existing.Object, "spec", "integration", "limit")
+			if !found || currentLimit != newLimit {
+				if err := unstructured.SetNestedField(existing.Object, newLimit, "spec", "integration", "limit"); err != nil {
+					return err
+				}
+				changed = true
+			}
+		}
+
+		if !changed {
+			return nil
+		}
+
+		_, err = fuseClient.Update(existing)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update fuse custom resource")
+	}
+
+	return &brokerapi.UpdateServiceInstanceResponse{
+		Code:      http.StatusAccepted,
+		Operation: "update",
 	}, nil
 }
 
 func (fd *FuseDeployer) RemoveDeploy(serviceInstanceId string, namespace string, k8sclient kubernetes.Interface) error {
+	// Cancel any Deploy work still queued or backing off for this instance so it doesn't race
+	// with the removal below and recreate resources we're about to delete.
+	if fd.worker != nil {
+		fd.worker.Cancel(serviceInstanceId)
+	}
+
 	ns := "fuse-" + serviceInstanceId
+
+	// Deploy may never have run in this process (e.g. broker restart then an immediate
+	// deprovision), so the recording sink can't rely on ensureWorker having started it already.
+	fd.ensureRecorder(k8sclient)
+
+	// Emit the terminal event before deleting the namespace it's scoped to, so it still
+	// reaches any external event sink even though the namespace (and its own Events) won't
+	// exist to `kubectl get events` afterwards.
+	username := ""
+	if fd.store != nil {
+		if op, err := fd.store.Get(serviceInstanceId); err == nil {
+			username = op.Username
+		}
+	}
+	fd.recordEvent(ns, serviceInstanceId, username, corev1.EventTypeNormal, "NamespaceRemoving", "removing namespace "+ns+" for fuse service")
+
 	err := k8sclient.CoreV1().Namespaces().Delete(ns, &metav1.DeleteOptions{})
 	if err != nil && !strings.Contains(err.Error(), "not found") {
 		glog.Errorf("failed to delete %s namespace: %+v", ns, err)
@@ -124,6 +376,13 @@ func (fd *FuseDeployer) RemoveDeploy(serviceInstanceId string, namespace string,
 	} else if err != nil && strings.Contains(err.Error(), "not found") {
 		glog.Infof("fuse namespace already deleted")
 	}
+
+	if fd.store != nil {
+		if err := fd.store.Delete(serviceInstanceId); err != nil {
+			glog.Errorf("failed to remove operation record for %s: %+v", serviceInstanceId, err)
+		}
+	}
+
 	return nil
 }
 
@@ -134,7 +393,21 @@ func (fd *FuseDeployer) LastOperation(instanceID string, k8sclient kubernetes.In
 	switch operation {
 	case "deploy":
 		glog.Infof("[LAST OPERATION:DEPLOY] Doing last operation for fuse: %s", namespace)
-		podsToWatch := []string{"syndesis-oauthproxy", "syndesis-server", "syndesis-ui"}
+
+		// The operation store is authoritative for an instance it has a record for, but it is
+		// only consulted once a Deploy has run in this broker process (ensureWorker creates it).
+		// Otherwise, and for instances with no record or if the store can't be read, the
+		// cluster is polled directly.
+		if fd.store != nil {
+			op, err := fd.store.Get(instanceID)
+			if err == nil {
+				return operationToLastOperationResponse(op), nil
+			}
+			if !kerrors.IsNotFound(err) {
+				glog.Errorf("failed to read operation record for %s, falling back to cluster check: %+v", instanceID, err)
+			}
+		}
+
 		dcClient, err := osclient.AppsClient()
 		if err != nil {
 			glog.Errorf("failed to create an openshift deployment config client: %+v", err)
@@ -144,33 +417,74 @@ func (fd *FuseDeployer) LastOperation(instanceID string, k8sclient kubernetes.In
 			}, errors.Wrap(err, "failed to create an openshift deployment config client")
 		}
 
-		nsObj, err := k8sclient.CoreV1().Namespaces().Get(namespace, metav1.GetOptions{})
+		// The Syndesis CR's status.phase is the authoritative success signal once the operator
+		// has started reporting it; DeploymentConfig readiness below is only a fallback for the
+		// window before the CR has any status at all, so renames/additions to the component set
+		// don't need a corresponding change here.
+		fuseClient, _, err := k8sClient.GetResourceClient("syndesis.io/v1alpha1", "Syndesis", namespace)
 		if err != nil {
-			glog.Infof("[LAST OPERATION:DEPLOY] Failed to get namespace obj for fuse: %s, returning in progress", namespace)
 			return &brokerapi.LastOperationResponse{
 				State:       brokerapi.StateFailed,
-				Description: "Failed to get namespace " + namespace + " for last operation check",
-			}, errors.Wrap(err, "failed to get namespace "+namespace+" for last operation check")
+				Description: "Failed to create fuse client",
+			}, errors.Wrap(err, "failed to create fuse client")
 		}
 
-		young := false
-		if time.Since(nsObj.ObjectMeta.CreationTimestamp.Time).Seconds() <= 120 {
-			young = true
+		fuseObj, err := fuseClient.Get(namespace, metav1.GetOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return &brokerapi.LastOperationResponse{
+				State:       brokerapi.StateFailed,
+				Description: "Failed to get fuse custom resource " + namespace,
+			}, errors.Wrap(err, "failed to get fuse custom resource "+namespace)
 		}
 
-		for _, v := range podsToWatch {
-			state, description, err := fd.getPodStatus(v, namespace, dcClient)
-			if state != brokerapi.StateSucceeded {
-				if young {
-					glog.Infof("[LAST OPERATION:DEPLOY] %s namespace is younger that 120 secs, returning in progress", namespace)
-					err = nil
-					state = brokerapi.StateInProgress
+		if fuseObj != nil {
+			phase, found, err := unstructured.NestedString(fuseObj.Object, "status", "phase")
+			if err != nil {
+				return &brokerapi.LastOperationResponse{
+					State:       brokerapi.StateFailed,
+					Description: "Failed to read fuse custom resource status",
+				}, errors.Wrap(err, "failed to read fuse custom resource status")
+			}
+
+			if found {
+				if phase == "Installed" {
+					glog.Infof("[LAST OPERATION:DEPLOY] fuse %s deployed successfully ", namespace)
+					return &brokerapi.LastOperationResponse{
+						State:       brokerapi.StateSucceeded,
+						Description: "fuse deployed successfully",
+					}, nil
 				}
-				glog.Infof("[LAST OPERATION:DEPLOY] %s namespace is older that 120 secs, returning actual state", namespace)
+				return &brokerapi.LastOperationResponse{
+					State:       brokerapi.StateInProgress,
+					Description: "fuse custom resource is in phase " + phase,
+				}, nil
+			}
+		}
+
+		dcs, err := dcClient.DeploymentConfigs(namespace).List(metav1.ListOptions{LabelSelector: syndesisComponentLabel})
+		if err != nil {
+			glog.Errorf("failed to list syndesis component deployment configs: %+v", err)
+			return &brokerapi.LastOperationResponse{
+				State:       brokerapi.StateFailed,
+				Description: "Failed to list syndesis component deployment configs",
+			}, errors.Wrap(err, "failed to list syndesis component deployment configs")
+		}
+
+		if len(dcs.Items) == 0 {
+			glog.Infof("[LAST OPERATION:DEPLOY] no syndesis components created yet for %s, returning in progress", namespace)
+			return &brokerapi.LastOperationResponse{
+				State:       brokerapi.StateInProgress,
+				Description: "namespace exists but resources missing - reconciling",
+			}, nil
+		}
+
+		for _, dc := range dcs.Items {
+			state, description := componentReadyState(&dc)
+			if state != brokerapi.StateSucceeded {
 				return &brokerapi.LastOperationResponse{
 					State:       state,
 					Description: description,
-				}, err
+				}, nil
 			}
 		}
 
@@ -179,6 +493,43 @@ func (fd *FuseDeployer) LastOperation(instanceID string, k8sclient kubernetes.In
 			State:       brokerapi.StateSucceeded,
 			Description: "fuse deployed successfully",
 		}, nil
+	case "update":
+		glog.Infof("[LAST OPERATION:UPDATE] Doing last operation for fuse: %s", namespace)
+		fuseClient, _, err := k8sClient.GetResourceClient("syndesis.io/v1alpha1", "Syndesis", namespace)
+		if err != nil {
+			return &brokerapi.LastOperationResponse{
+				State:       brokerapi.StateFailed,
+				Description: "Failed to create fuse client",
+			}, errors.Wrap(err, "failed to create fuse client")
+		}
+
+		fuseObj, err := fuseClient.Get(namespace, metav1.GetOptions{})
+		if err != nil {
+			return &brokerapi.LastOperationResponse{
+				State:       brokerapi.StateFailed,
+				Description: "Failed to get fuse custom resource " + namespace,
+			}, errors.Wrap(err, "failed to get fuse custom resource "+namespace)
+		}
+
+		phase, _, err := unstructured.NestedString(fuseObj.Object, "status", "phase")
+		if err != nil {
+			return &brokerapi.LastOperationResponse{
+				State:       brokerapi.StateFailed,
+				Description: "Failed to read fuse custom resource status",
+			}, errors.Wrap(err, "failed to read fuse custom resource status")
+		}
+
+		if phase == "Installed" {
+			return &brokerapi.LastOperationResponse{
+				State:       brokerapi.StateSucceeded,
+				Description: "fuse updated successfully",
+			}, nil
+		}
+
+		return &brokerapi.LastOperationResponse{
+			State:       brokerapi.StateInProgress,
+			Description: "fuse update in progress, operator reconciling",
+		}, nil
 	case "remove":
 		_, err := k8sclient.CoreV1().Namespaces().Get(namespace, metav1.GetOptions{})
 		if err != nil && strings.Contains(err.Error(), "not found") {
@@ -205,17 +556,61 @@ func (fd *FuseDeployer) LastOperation(instanceID string, k8sclient kubernetes.In
 	}
 }
 
+// reconcileOrCreate implements the Get -> Create-if-NotFound -> Update-on-conflict-retry
+// pattern so provisioning can be retried safely after a partial failure. get should report
+// whether the resource already exists, create should create it from scratch, and update
+// should bring an existing resource in line with the desired spec; update is retried under
+// retry.RetryOnConflict since it may race with the operator reconciling the same resource.
+func reconcileOrCreate(get func() (bool, error), create func() error, update func() error) error {
+	exists, err := get()
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		err = create()
+		if err != nil && !kerrors.IsAlreadyExists(err) {
+			return err
+		}
+		return nil
+	}
+
+	if update == nil {
+		return nil
+	}
+
+	return retry.RetryOnConflict(retry.DefaultBackoff, update)
+}
+
 func (fd *FuseDeployer) createRoleBindings(namespace string, userInfo v1.UserInfo, k8sclient kubernetes.Interface, osClientFactory *openshift.ClientFactory) error {
+	rbClient := k8sclient.RbacV1beta1().RoleBindings(namespace)
+
 	for _, sysRoleBinding := range getSystemRoleBindings(namespace) {
-		_, err := k8sclient.RbacV1beta1().RoleBindings(namespace).Create(&sysRoleBinding)
-		if err != nil && !strings.Contains(err.Error(), "already exists") {
-			return errors.Wrapf(err, "failed to create rolebinding for %s", &sysRoleBinding.ObjectMeta.Name)
+		sysRoleBinding := sysRoleBinding
+		err := reconcileOrCreate(
+			func() (bool, error) {
+				_, err := rbClient.Get(sysRoleBinding.Name, metav1.GetOptions{})
+				return getExists(err)
+			},
+			func() error { _, err := rbClient.Create(&sysRoleBinding); return err },
+			func() error { _, err := rbClient.Update(&sysRoleBinding); return err },
+		)
+		if err != nil {
+			return errors.Wrapf(err, "failed to reconcile rolebinding for %s", sysRoleBinding.ObjectMeta.Name)
 		}
 	}
 
-	_, err := k8sclient.RbacV1beta1().RoleBindings(namespace).Create(getInstallRoleBindingObj())
+	installRoleBinding := getInstallRoleBindingObj()
+	err := reconcileOrCreate(
+		func() (bool, error) {
+			_, err := rbClient.Get(installRoleBinding.Name, metav1.GetOptions{})
+			return getExists(err)
+		},
+		func() error { _, err := rbClient.Create(installRoleBinding); return err },
+		func() error { _, err := rbClient.Update(installRoleBinding); return err },
+	)
 	if err != nil {
-		return errors.Wrap(err, "failed to create install role binding for fuse service")
+		return errors.Wrap(err, "failed to reconcile install role binding for fuse service")
 	}
 
 	authClient, err := osClientFactory.AuthClient()
@@ -224,23 +619,46 @@ func (fd *FuseDeployer) createRoleBindings(namespace string, userInfo v1.UserInf
 	}
 
 	_, err = authClient.RoleBindings(namespace).Create(getViewRoleBindingObj())
-	if err != nil {
+	if err != nil && !kerrors.IsAlreadyExists(err) {
 		return errors.Wrap(err, "failed to create view role binding for fuse service")
 	}
 
 	_, err = authClient.RoleBindings(namespace).Create(getEditRoleBindingObj())
-	if err != nil {
+	if err != nil && !kerrors.IsAlreadyExists(err) {
 		return errors.Wrap(err, "failed to create edit role binding for fuse service")
 	}
 
-	_, err = authClient.RoleBindings(namespace).Create(getUserViewRoleBindingObj(namespace, userInfo.Username))
+	// the user view role binding's subject changes with the requesting user, so an existing
+	// binding from a previous partial deploy needs to be updated rather than left stale
+	userViewRoleBinding := getUserViewRoleBindingObj(namespace, userInfo.Username)
+	err = reconcileOrCreate(
+		func() (bool, error) {
+			_, err := authClient.RoleBindings(namespace).Get(userViewRoleBinding.Name, metav1.GetOptions{})
+			return getExists(err)
+		},
+		func() error { _, err := authClient.RoleBindings(namespace).Create(userViewRoleBinding); return err },
+		func() error { _, err := authClient.RoleBindings(namespace).Update(userViewRoleBinding); return err },
+	)
 	if err != nil {
-		return errors.Wrap(err, "failed to create user view role binding for fuse service")
+		return errors.Wrap(err, "failed to reconcile user view role binding for fuse service")
 	}
 
 	return nil
 }
 
+// getExists turns the error from any Get call into the (exists, error) shape reconcileOrCreate
+// expects: a NotFound error means "doesn't exist yet", any other error is a real failure, and
+// a nil error means the resource is there to be updated.
+func getExists(err error) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+	if kerrors.IsNotFound(err) {
+		return false, nil
+	}
+	return false, err
+}
+
 func (fd *FuseDeployer) createImageStream(namespace string, osClientFactory *openshift.ClientFactory) error {
 	imageClient, err := osClientFactory.ImageStreamClient()
 	if err != nil {
@@ -249,7 +667,7 @@ func (fd *FuseDeployer) createImageStream(namespace string, osClientFactory *ope
 
 	for _, imgStream := range getFuseOnlineImageStreamsObj() {
 		_, err = imageClient.ImageStreams(namespace).Create(&imgStream)
-		if err != nil {
+		if err != nil && !kerrors.IsAlreadyExists(err) {
 			return errors.Wrap(err, "failed to create "+imgStream.ObjectMeta.Name+" image stream for fuse service")
 		}
 	}
@@ -264,7 +682,7 @@ func (fd *FuseDeployer) createFuseOperator(namespace string, osClientFactory *op
 	}
 
 	_, err = dcClient.DeploymentConfigs(namespace).Create(getDeploymentConfigObj())
-	if err != nil {
+	if err != nil && !kerrors.IsAlreadyExists(err) {
 		return errors.Wrap(err, "failed to create deployment config for fuse service")
 	}
 
@@ -277,7 +695,7 @@ func (fd *FuseDeployer) createFuseCustomResource(namespace, brokerNamespace, use
 		return "", errors.Wrap(err, "failed to create fuse client")
 	}
 
-	integrationsLimit := 0
+	integrationsLimit := brokerapi.Config().Syndesis.IntegrationsLimit
 	if parameters["limit"] != nil {
 		integrationsLimit = int(parameters["limit"].(float64))
 	}
@@ -288,38 +706,81 @@ func (fd *FuseDeployer) createFuseCustomResource(namespace, brokerNamespace, use
 	fuseDashboardURL := fd.getRouteHostname(namespace)
 
 	fuseObj.Spec.RouteHostName = fuseDashboardURL
-	_, err = fuseClient.Create(k8sutil.UnstructuredFromRuntimeObject(fuseObj))
+	fuseUnstructured := k8sutil.UnstructuredFromRuntimeObject(fuseObj)
+
+	// The Syndesis CR is mutable like the RoleBindings above: a retried PUT, or an async
+	// reconcile retry after a later step failed, can land here a second time and must patch
+	// rather than fail on AlreadyExists.
+	err = reconcileOrCreate(
+		func() (bool, error) {
+			_, err := fuseClient.Get(namespace, metav1.GetOptions{})
+			return getExists(err)
+		},
+		func() error { _, err := fuseClient.Create(fuseUnstructured); return err },
+		func() error { _, err := fuseClient.Update(fuseUnstructured); return err },
+	)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to create a fuse custom resource")
+		return "", errors.Wrap(err, "failed to reconcile fuse custom resource")
 	}
 
 	return "https://" + fuseDashboardURL, nil
 }
 
-// Get route hostname for fuse
+// Get route hostname for fuse. The route suffix configured via the deployer ConfigMap takes
+// priority so it can be changed at runtime; the ROUTE_SUFFIX env var remains as a fallback
+// for deployments that haven't moved to the mounted config yet.
 func (fd *FuseDeployer) getRouteHostname(namespace string) string {
 	routeHostname := namespace
-	routeSuffix, exists := os.LookupEnv("ROUTE_SUFFIX")
-	if exists {
+	routeSuffix := brokerapi.Config().RouteSuffix
+	if routeSuffix == "" {
+		routeSuffix, _ = os.LookupEnv("ROUTE_SUFFIX")
+	}
+	if routeSuffix != "" {
 		routeHostname = routeHostname + "." + routeSuffix
 	}
 	return routeHostname
 }
 
-func (fd *FuseDeployer) getPodStatus(podName, namespace string, dcClient *appsv1.AppsV1Client) (string, string, error) {
-	pod, err := dcClient.DeploymentConfigs(namespace).Get(podName, metav1.GetOptions{})
-	if err != nil {
-		glog.Errorf("Failed to get status of %s: %+v", podName, err)
-		return brokerapi.StateFailed,
-			"Failed to get status of " + podName,
-			errors.Wrap(err, "failed to get status of "+podName)
-	}
+// syndesisComponentLabel is set by the fuse operator on every DeploymentConfig it manages,
+// and is used to discover the current set of components instead of hardcoding their names.
+const syndesisComponentLabel = "syndesis.io/component"
 
-	for _, v := range pod.Status.Conditions {
+// componentReadyState reports whether a single Syndesis component's DeploymentConfig is
+// ready. A component with a Ready condition of False is reported as in progress, whatever
+// its reason; no reason is treated as fatal, so this never reports failed.
+func componentReadyState(dc *osappsv1.DeploymentConfig) (string, string) {
+	for _, v := range dc.Status.Conditions {
 		if v.Type == "Ready" && v.Status == "False" {
-			return brokerapi.StateInProgress, v.Message, nil
+			return brokerapi.StateInProgress, dc.ObjectMeta.Name + ": " + v.Message
 		}
 	}
 
-	return brokerapi.StateSucceeded, "", nil
+	return brokerapi.StateSucceeded, ""
+}
+
+// operationToLastOperationResponse translates a persisted async.Operation into the broker's
+// LastOperationResponse, reporting the step and any last error while the operation is still
+// in progress so a stuck reconcile is visible to whoever is polling.
+func operationToLastOperationResponse(op *async.Operation) *brokerapi.LastOperationResponse {
+	switch op.State {
+	case async.StateSucceeded:
+		return &brokerapi.LastOperationResponse{
+			State:       brokerapi.StateSucceeded,
+			Description: op.Description,
+		}
+	case async.StateFailed:
+		return &brokerapi.LastOperationResponse{
+			State:       brokerapi.StateFailed,
+			Description: op.Description,
+		}
+	default:
+		description := "fuse provisioning in progress, step: " + op.Step
+		if op.LastError != "" {
+			description = description + " (last attempt failed: " + op.LastError + ")"
+		}
+		return &brokerapi.LastOperationResponse{
+			State:       brokerapi.StateInProgress,
+			Description: description,
+		}
+	}
 }
@@ -0,0 +1,50 @@
+package fuse
+
+import (
+	"testing"
+
+	brokerapi "github.com/integr8ly/managed-service-broker/pkg/broker"
+	"github.com/integr8ly/managed-service-broker/pkg/broker/async"
+)
+
+func TestOperationToLastOperationResponseSucceeded(t *testing.T) {
+	op := &async.Operation{State: async.StateSucceeded, Description: "fuse deployed successfully"}
+
+	resp := operationToLastOperationResponse(op)
+
+	if resp.State != brokerapi.StateSucceeded {
+		t.Errorf("expected state %q, got %q", brokerapi.StateSucceeded, resp.State)
+	}
+	if resp.Description != op.Description {
+		t.Errorf("expected description %q, got %q", op.Description, resp.Description)
+	}
+}
+
+func TestOperationToLastOperationResponseFailed(t *testing.T) {
+	op := &async.Operation{State: async.StateFailed, Description: "fuse provisioning failed after 10 attempts: boom"}
+
+	resp := operationToLastOperationResponse(op)
+
+	if resp.State != brokerapi.StateFailed {
+		t.Errorf("expected state %q, got %q", brokerapi.StateFailed, resp.State)
+	}
+	if resp.Description != op.Description {
+		t.Errorf("expected description %q, got %q", op.Description, resp.Description)
+	}
+}
+
+func TestOperationToLastOperationResponseInProgress(t *testing.T) {
+	op := &async.Operation{State: async.StateInProgress, Step: "creating namespace"}
+
+	resp := operationToLastOperationResponse(op)
+
+	if resp.State != brokerapi.StateInProgress {
+		t.Errorf("expected state %q, got %q", brokerapi.StateInProgress, resp.State)
+	}
+
+	op.LastError = "conflict"
+	resp = operationToLastOperationResponse(op)
+	if resp.Description == "" {
+		t.Error("expected a non-empty description once a last error is recorded")
+	}
+}
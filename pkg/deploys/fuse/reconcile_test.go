@@ -0,0 +1,95 @@
+package fuse
+
+import (
+	"testing"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func notFoundErr() error {
+	return kerrors.NewNotFound(schema.GroupResource{Resource: "widgets"}, "test")
+}
+
+func TestReconcileOrCreateCreatesWhenMissing(t *testing.T) {
+	created := false
+	updated := false
+
+	err := reconcileOrCreate(
+		func() (bool, error) { return false, nil },
+		func() error { created = true; return nil },
+		func() error { updated = true; return nil },
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !created {
+		t.Error("expected create to be called")
+	}
+	if updated {
+		t.Error("expected update not to be called")
+	}
+}
+
+func TestReconcileOrCreateUpdatesWhenExists(t *testing.T) {
+	created := false
+	updated := false
+
+	err := reconcileOrCreate(
+		func() (bool, error) { return true, nil },
+		func() error { created = true; return nil },
+		func() error { updated = true; return nil },
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if created {
+		t.Error("expected create not to be called")
+	}
+	if !updated {
+		t.Error("expected update to be called")
+	}
+}
+
+func TestReconcileOrCreateTreatsAlreadyExistsOnCreateAsSuccess(t *testing.T) {
+	err := reconcileOrCreate(
+		func() (bool, error) { return false, nil },
+		func() error { return kerrors.NewAlreadyExists(schema.GroupResource{Resource: "widgets"}, "test") },
+		nil,
+	)
+
+	if err != nil {
+		t.Fatalf("expected AlreadyExists on create to be swallowed, got %v", err)
+	}
+}
+
+func TestReconcileOrCreatePropagatesGetError(t *testing.T) {
+	getErr := kerrors.NewInternalError(notFoundErr())
+
+	err := reconcileOrCreate(
+		func() (bool, error) { return false, getErr },
+		func() error { t.Fatal("create should not be called"); return nil },
+		nil,
+	)
+
+	if err == nil {
+		t.Fatal("expected get error to propagate")
+	}
+}
+
+func TestGetExists(t *testing.T) {
+	if exists, err := getExists(nil); !exists || err != nil {
+		t.Errorf("expected (true, nil) for nil error, got (%v, %v)", exists, err)
+	}
+
+	if exists, err := getExists(notFoundErr()); exists || err != nil {
+		t.Errorf("expected (false, nil) for NotFound error, got (%v, %v)", exists, err)
+	}
+
+	other := kerrors.NewInternalError(notFoundErr())
+	if exists, err := getExists(other); exists || err == nil {
+		t.Errorf("expected (false, err) for a non-NotFound error, got (%v, %v)", exists, err)
+	}
+}
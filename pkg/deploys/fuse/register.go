@@ -0,0 +1,11 @@
+package fuse
+
+import (
+	brokerapi "github.com/integr8ly/managed-service-broker/pkg/broker"
+)
+
+func init() {
+	brokerapi.Register("fuse-service-id", func(id string) brokerapi.Deployer {
+		return NewDeployer(id)
+	})
+}